@@ -29,15 +29,33 @@ type ModelRegistryCache struct {
 	*corev1.Volume `json:",inline"`
 }
 
+// ModelRegistryStatus is the observed state of a ModelRegistry resource
+type ModelRegistryStatus struct {
+	// Conditions is the set of standard Kubernetes conditions for this ModelRegistry; the controller sets Ready
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ObservedGeneration is the metadata.generation that was last reconciled
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastSyncTime is the last time the ModelRegistry was successfully reconciled
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // ModelRegistry is the Schema for the ModelRegistry API
 // +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 type ModelRegistry struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
-	Spec              ModelRegistrySpec `json:"spec,omitempty"`
+	Spec              ModelRegistrySpec   `json:"spec,omitempty"`
+	Status            ModelRegistryStatus `json:"status,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
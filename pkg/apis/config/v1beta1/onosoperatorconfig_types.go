@@ -0,0 +1,71 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cfg "sigs.k8s.io/controller-runtime/pkg/config/v1alpha1"
+)
+
+// ControllerConfig is the set of tuning knobs read from OnosOperatorConfig for a single controller
+type ControllerConfig struct {
+	// MaxConcurrentReconciles is the maximum number of concurrent Reconciles the controller will run
+	MaxConcurrentReconciles int `json:"maxConcurrentReconciles,omitempty"`
+}
+
+// ModelRegistryDefaults holds the defaults applied to a ModelRegistry resource's Spec where it leaves a
+// field unset
+type ModelRegistryDefaults struct {
+	Cache ModelRegistryCache `json:"cache,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// OnosOperatorConfig configures the onos-operator binary itself. It is loaded at startup via
+// --config=onos-operator.yaml, can be reloaded by a config.Watcher when that file changes, and embeds the standard controller-runtime ComponentConfig fields
+// (leader election, metrics/health addresses, etc.) alongside the tunables specific to this operator.
+type OnosOperatorConfig struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// ControllerManagerConfigurationSpec embeds the standard controller-runtime ComponentConfig fields
+	cfg.ControllerManagerConfigurationSpec `json:",inline"`
+
+	// TopoServiceName is the name of the onos-topo Service the topo controllers connect to.
+	// Defaults to "onos-topo".
+	TopoServiceName string `json:"topoServiceName,omitempty"`
+
+	// TopoConnectTimeout bounds how long a controller waits to connect to onos-topo. Defaults to 5s.
+	TopoConnectTimeout metav1.Duration `json:"topoConnectTimeout,omitempty"`
+
+	// ReconcileTimeout bounds how long a single Reconcile call may run before its context is
+	// cancelled. Defaults to 30s.
+	ReconcileTimeout metav1.Duration `json:"reconcileTimeout,omitempty"`
+
+	// FinalizerName is the finalizer the topo controllers add to CRs they manage in onos-topo.
+	// Defaults to "topo".
+	FinalizerName string `json:"finalizerName,omitempty"`
+
+	// Controllers maps a controller name (e.g. "topo-kind-controller") to its tuning knobs. A
+	// controller not present in this map runs with controller-runtime's own defaults.
+	Controllers map[string]ControllerConfig `json:"controllers,omitempty"`
+
+	// ModelRegistryDefaults is applied to ModelRegistry resources that leave the corresponding Spec
+	// field unset.
+	ModelRegistryDefaults ModelRegistryDefaults `json:"modelRegistryDefaults,omitempty"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OnosOperatorConfig{})
+}
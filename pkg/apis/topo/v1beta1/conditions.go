@@ -0,0 +1,44 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+const (
+	// ConditionReady indicates whether the topo object has been reconciled successfully
+	ConditionReady = "Ready"
+
+	// ConditionSynced indicates whether the in-cluster spec matches the object stored in onos-topo
+	ConditionSynced = "Synced"
+
+	// ConditionTopoReachable indicates whether the onos-topo gRPC service could be reached on the last reconcile
+	ConditionTopoReachable = "TopoReachable"
+)
+
+const (
+	// ReasonTopoUnreachable is set when the onos-topo service could not be reached
+	ReasonTopoUnreachable = "TopoUnreachable"
+
+	// ReasonAlreadyExists is set when the object already existed in onos-topo prior to reconciliation and
+	// its owned fields already matched the in-cluster spec
+	ReasonAlreadyExists = "AlreadyExists"
+
+	// ReasonSynced is set once the object has been created or updated in onos-topo
+	ReasonSynced = "Synced"
+
+	// ReasonDrifted is set when the in-cluster spec no longer matches the object stored in onos-topo
+	ReasonDrifted = "Drifted"
+
+	// ReasonGetFailed is set when onos-topo was reachable but the lookup of the object itself failed
+	ReasonGetFailed = "GetFailed"
+)
@@ -0,0 +1,69 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RelationSpec is the k8s spec for a Relation resource
+type RelationSpec struct {
+	KindName   string            `json:"kindName,omitempty"`
+	SrcEntity  string            `json:"srcEntity,omitempty"`
+	TgtEntity  string            `json:"tgtEntity,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// RelationStatus is the observed state of a Relation resource
+type RelationStatus struct {
+	// Conditions is the set of standard Kubernetes conditions for this Relation, e.g. Ready, Synced, TopoReachable
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ObservedGeneration is the metadata.generation that was last reconciled against onos-topo
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastSyncTime is the last time the Relation was successfully synced with onos-topo
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Relation is the Schema for the Relation API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+type Relation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              RelationSpec   `json:"spec,omitempty"`
+	Status            RelationStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RelationList contains a list of Relation
+type RelationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Relation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Relation{}, &RelationList{})
+}
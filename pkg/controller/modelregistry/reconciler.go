@@ -0,0 +1,139 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modelregistry
+
+import (
+	"context"
+
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/onos-operator/pkg/apis/config/v1beta1"
+	"github.com/onosproject/onos-operator/pkg/controller/util/config"
+	ctxlog "github.com/onosproject/onos-operator/pkg/controller/util/logging"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var log = logging.GetLogger("controller", "modelregistry")
+
+const controllerName = "modelregistry-controller"
+
+// Add creates a new ModelRegistry controller and adds it to the Manager. The Manager will set fields on the
+// controller and Start it when the Manager is Started. cfg supplies the reconcile timeout, worker
+// concurrency, and the ModelRegistryDefaults applied to a ModelRegistry that leaves Spec fields unset.
+func Add(mgr manager.Manager, cfg *config.Store) error {
+	r := &Reconciler{
+		client: mgr.GetClient(),
+		scheme: mgr.GetScheme(),
+		cfg:    cfg,
+	}
+
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: config.MaxConcurrentReconciles(cfg.Get(), controllerName),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource ModelRegistry
+	err = c.Watch(&source.Kind{Type: &v1beta1.ModelRegistry{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var _ reconcile.Reconciler = &Reconciler{}
+
+// Reconciler reconciles a ModelRegistry object
+type Reconciler struct {
+	client client.Client
+	scheme *runtime.Scheme
+	cfg    *config.Store
+}
+
+// Reconcile reads that state of the cluster for a ModelRegistry object and makes changes based on the state read
+// and what is in the ModelRegistry.Spec
+func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.Get().ReconcileTimeout.Duration)
+	defer cancel()
+
+	ctx, reqLog := ctxlog.NewReconcileContext(ctx, log, "ModelRegistry", request)
+	reqLog.Infof("Reconciling ModelRegistry %s/%s", request.Namespace, request.Name)
+
+	// Fetch the ModelRegistry instance
+	registry := &v1beta1.ModelRegistry{}
+	err := r.client.Get(ctx, request.NamespacedName, registry)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	if registry.Status.ObservedGeneration == registry.Generation {
+		return reconcile.Result{}, nil
+	}
+
+	if registry.Spec.Cache.Volume == nil {
+		registry.Spec.Cache = r.cfg.Get().ModelRegistryDefaults.Cache
+		if err := r.client.Update(ctx, registry); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	setCondition(registry, metav1.Condition{
+		Type:   v1beta1.ConditionReady,
+		Status: metav1.ConditionTrue,
+		Reason: v1beta1.ReasonSynced,
+	})
+	registry.Status.ObservedGeneration = registry.Generation
+	now := metav1.Now()
+	registry.Status.LastSyncTime = &now
+
+	if err := r.client.Status().Update(ctx, registry); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+func setCondition(registry *v1beta1.ModelRegistry, condition metav1.Condition) {
+	condition.ObservedGeneration = registry.Generation
+	condition.LastTransitionTime = metav1.Now()
+	for i, existing := range registry.Status.Conditions {
+		if existing.Type == condition.Type {
+			if existing.Status != condition.Status {
+				registry.Status.Conditions[i] = condition
+			} else {
+				condition.LastTransitionTime = existing.LastTransitionTime
+				registry.Status.Conditions[i] = condition
+			}
+			return
+		}
+	}
+	registry.Status.Conditions = append(registry.Status.Conditions, condition)
+}
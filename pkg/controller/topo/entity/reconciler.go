@@ -0,0 +1,365 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entity
+
+import (
+	"context"
+
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
+	"github.com/onosproject/onos-operator/pkg/controller/util/config"
+	"github.com/onosproject/onos-operator/pkg/controller/util/grpc"
+	"github.com/onosproject/onos-operator/pkg/controller/util/k8s"
+	ctxlog "github.com/onosproject/onos-operator/pkg/controller/util/logging"
+	"github.com/onosproject/onos-operator/pkg/controller/util/topodiff"
+	"google.golang.org/grpc/status"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var log = logging.GetLogger("controller", "topo", "entity")
+
+const controllerName = "topo-entity-controller"
+
+// Add creates a new Entity controller and adds it to the Manager. The Manager will set fields on the
+// controller and Start it when the Manager is Started. cfg supplies the tunables (topo service name,
+// finalizer name, timeouts, worker concurrency).
+func Add(mgr manager.Manager, cfg *config.Store) error {
+	r := &Reconciler{
+		client:     mgr.GetClient(),
+		scheme:     mgr.GetScheme(),
+		restConfig: mgr.GetConfig(),
+		cfg:        cfg,
+	}
+
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: config.MaxConcurrentReconciles(cfg.Get(), controllerName),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource Entity
+	err = c.Watch(&source.Kind{Type: &v1beta1.Entity{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes made to the Entity's topo object directly in onos-topo so drift from other
+	// writers is picked up without waiting on the next update to the CR
+	topoSource := &grpc.TopoWatchSource{
+		GVK:       v1beta1.SchemeGroupVersion.WithKind("Entity"),
+		Namespace: grpc.WatchNamespace(),
+	}
+	if err := c.Watch(topoSource, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+	if err := topoSource.AddToManager(mgr); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var _ reconcile.Reconciler = &Reconciler{}
+
+// Reconciler reconciles an Entity object
+type Reconciler struct {
+	client     client.Client
+	scheme     *runtime.Scheme
+	restConfig *rest.Config
+	cfg        *config.Store
+}
+
+// Reconcile reads that state of the cluster for an Entity object and makes changes based on the state read
+// and what is in the Entity.Spec
+func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.Get().ReconcileTimeout.Duration)
+	defer cancel()
+
+	ctx, reqLog := ctxlog.NewReconcileContext(ctx, log, "Entity", request)
+	reqLog.Infof("Reconciling Entity %s/%s", request.Namespace, request.Name)
+
+	// Fetch the Entity instance
+	entity := &v1beta1.Entity{}
+	err := r.client.Get(ctx, request.NamespacedName, entity)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	if entity.DeletionTimestamp == nil {
+		return r.reconcileCreate(ctx, entity)
+	} else {
+		return r.reconcileDelete(ctx, entity)
+	}
+}
+
+func (r *Reconciler) reconcileCreate(ctx context.Context, entity *v1beta1.Entity) (reconcile.Result, error) {
+	cfg := r.cfg.Get()
+
+	// Add the finalizer to the entity if necessary
+	if !k8s.HasFinalizer(entity, cfg.FinalizerName) {
+		k8s.AddFinalizer(entity, cfg.FinalizerName)
+		err := r.client.Update(ctx, entity)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Connect to the topology service
+	connectCtx, cancel := context.WithTimeout(ctx, cfg.TopoConnectTimeout.Duration)
+	defer cancel()
+	conn, err := grpc.ConnectService(connectCtx, r.client, entity.Namespace, cfg.TopoServiceName)
+	if err != nil {
+		r.setCondition(entity, metav1.Condition{
+			Type:    v1beta1.ConditionTopoReachable,
+			Status:  metav1.ConditionFalse,
+			Reason:  v1beta1.ReasonTopoUnreachable,
+			Message: err.Error(),
+		})
+		return reconcile.Result{}, r.updateStatus(ctx, entity)
+	}
+	defer conn.Close()
+
+	client := topo.NewTopoClient(conn)
+	r.setCondition(entity, metav1.Condition{
+		Type:   v1beta1.ConditionTopoReachable,
+		Status: metav1.ConditionTrue,
+		Reason: "Connected",
+	})
+
+	// Fetch the object as stored in onos-topo, if it exists
+	object, exists, err := r.getEntity(ctx, entity, client)
+	if err != nil {
+		r.setCondition(entity, metav1.Condition{
+			Type:    v1beta1.ConditionSynced,
+			Status:  metav1.ConditionFalse,
+			Reason:  v1beta1.ReasonGetFailed,
+			Message: err.Error(),
+		})
+		if statusErr := r.updateStatus(ctx, entity); statusErr != nil {
+			return reconcile.Result{}, statusErr
+		}
+		return reconcile.Result{}, err
+	}
+
+	if !exists {
+		// The entity does not exist in onos-topo yet; create it
+		if err := r.createEntity(ctx, entity, client); err != nil {
+			return reconcile.Result{}, err
+		}
+		r.setCondition(entity, metav1.Condition{
+			Type:   v1beta1.ConditionSynced,
+			Status: metav1.ConditionTrue,
+			Reason: v1beta1.ReasonSynced,
+		})
+	} else if merged, drifted, err := topodiff.Merge(desiredEntity(entity), object, topodiff.OwnedPaths(entity.Annotations)); err != nil {
+		return reconcile.Result{}, err
+	} else if drifted {
+		// The owned fields of the in-cluster spec have drifted from onos-topo; re-issue the update
+		// with only the owned paths merged in, leaving everything else as onos-topo reported it
+		if err := r.updateEntity(ctx, entity, merged, client); err != nil {
+			r.setCondition(entity, metav1.Condition{
+				Type:    v1beta1.ConditionSynced,
+				Status:  metav1.ConditionFalse,
+				Reason:  v1beta1.ReasonDrifted,
+				Message: err.Error(),
+			})
+			return reconcile.Result{}, r.updateStatus(ctx, entity)
+		}
+		r.setCondition(entity, metav1.Condition{
+			Type:   v1beta1.ConditionSynced,
+			Status: metav1.ConditionTrue,
+			Reason: v1beta1.ReasonSynced,
+		})
+	} else {
+		r.setCondition(entity, metav1.Condition{
+			Type:   v1beta1.ConditionSynced,
+			Status: metav1.ConditionTrue,
+			Reason: v1beta1.ReasonAlreadyExists,
+		})
+	}
+
+	r.setCondition(entity, metav1.Condition{
+		Type:   v1beta1.ConditionReady,
+		Status: metav1.ConditionTrue,
+		Reason: v1beta1.ReasonSynced,
+	})
+	entity.Status.ObservedGeneration = entity.Generation
+	now := metav1.Now()
+	entity.Status.LastSyncTime = &now
+	return reconcile.Result{}, r.updateStatus(ctx, entity)
+}
+
+func (r *Reconciler) reconcileDelete(ctx context.Context, entity *v1beta1.Entity) (reconcile.Result, error) {
+	cfg := r.cfg.Get()
+
+	// If the entity has already been finalized, exit reconciliation
+	if !k8s.HasFinalizer(entity, cfg.FinalizerName) {
+		return reconcile.Result{}, nil
+	}
+
+	// Connect to the topology service
+	connectCtx, cancel := context.WithTimeout(ctx, cfg.TopoConnectTimeout.Duration)
+	defer cancel()
+	conn, err := grpc.ConnectService(connectCtx, r.client, entity.Namespace, cfg.TopoServiceName)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	defer conn.Close()
+
+	client := topo.NewTopoClient(conn)
+
+	// Delete the entity from the topology
+	if err := r.deleteEntity(ctx, entity, client); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Once the entity has been deleted, remove the topology finalizer
+	k8s.RemoveFinalizer(entity, cfg.FinalizerName)
+	if err := r.client.Update(ctx, entity); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+func (r *Reconciler) getEntity(ctx context.Context, entity *v1beta1.Entity, client topo.TopoClient) (*topo.Object, bool, error) {
+	request := &topo.GetRequest{
+		ID: topo.ID(entity.Name),
+	}
+	response, err := client.Get(ctx, request)
+	if err == nil {
+		return response.Object, true, nil
+	}
+
+	stat, ok := status.FromError(err)
+	if !ok {
+		return nil, false, err
+	}
+
+	err = errors.FromStatus(stat)
+	if !errors.IsNotFound(err) {
+		return nil, false, err
+	}
+	return nil, false, nil
+}
+
+// desiredEntity returns the topo.Object representing the full desired state of an Entity, used as the
+// local side of a topodiff.Merge against what onos-topo currently reports
+func desiredEntity(entity *v1beta1.Entity) *topo.Object {
+	return &topo.Object{
+		ID:   topo.ID(entity.Name),
+		Type: topo.Object_ENTITY,
+		Obj: &topo.Object_Entity{
+			Entity: &topo.Entity{
+				KindID: topo.ID(entity.Spec.KindName),
+			},
+		},
+		Attributes: entity.Spec.Attributes,
+	}
+}
+
+func (r *Reconciler) createEntity(ctx context.Context, entity *v1beta1.Entity, client topo.TopoClient) error {
+	request := &topo.CreateRequest{
+		Object: desiredEntity(entity),
+	}
+
+	_, err := client.Create(ctx, request)
+	if err == nil {
+		return nil
+	}
+
+	stat, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	err = errors.FromStatus(stat)
+	if !errors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+func (r *Reconciler) updateEntity(ctx context.Context, entity *v1beta1.Entity, merged *topo.Object, client topo.TopoClient) error {
+	request := &topo.UpdateRequest{
+		Object: merged,
+	}
+
+	_, err := client.Update(ctx, request)
+	return err
+}
+
+func (r *Reconciler) deleteEntity(ctx context.Context, entity *v1beta1.Entity, client topo.TopoClient) error {
+	request := &topo.DeleteRequest{
+		ID: topo.ID(entity.Name),
+	}
+
+	_, err := client.Delete(ctx, request)
+	if err == nil {
+		return nil
+	}
+
+	stat, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	err = errors.FromStatus(stat)
+	if !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// setCondition sets or updates a condition on the Entity's status, preserving LastTransitionTime when the
+// condition's status is unchanged
+func (r *Reconciler) setCondition(entity *v1beta1.Entity, condition metav1.Condition) {
+	condition.ObservedGeneration = entity.Generation
+	condition.LastTransitionTime = metav1.Now()
+	for i, existing := range entity.Status.Conditions {
+		if existing.Type == condition.Type {
+			if existing.Status == condition.Status {
+				condition.LastTransitionTime = existing.LastTransitionTime
+			}
+			entity.Status.Conditions[i] = condition
+			return
+		}
+	}
+	entity.Status.Conditions = append(entity.Status.Conditions, condition)
+}
+
+func (r *Reconciler) updateStatus(ctx context.Context, entity *v1beta1.Entity) error {
+	return r.client.Status().Update(ctx, entity)
+}
@@ -16,14 +16,19 @@ package kind
 
 import (
 	"context"
+
 	"github.com/onosproject/onos-api/go/onos/topo"
 	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
 	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
+	"github.com/onosproject/onos-operator/pkg/controller/util/config"
 	"github.com/onosproject/onos-operator/pkg/controller/util/grpc"
 	"github.com/onosproject/onos-operator/pkg/controller/util/k8s"
+	ctxlog "github.com/onosproject/onos-operator/pkg/controller/util/logging"
+	"github.com/onosproject/onos-operator/pkg/controller/util/topodiff"
 	"google.golang.org/grpc/status"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -36,20 +41,24 @@ import (
 
 var log = logging.GetLogger("controller", "topo", "kind")
 
-const topoService = "onos-topo"
-const topoFinalizer = "topo"
+const controllerName = "topo-kind-controller"
 
 // Add creates a new Kind controller and adds it to the Manager. The Manager will set fields on the
-// controller and Start it when the Manager is Started.
-func Add(mgr manager.Manager) error {
+// controller and Start it when the Manager is Started. cfg supplies the tunables (topo service name,
+// finalizer name, timeouts, worker concurrency) that used to be hardcoded constants in this package.
+func Add(mgr manager.Manager, cfg *config.Store) error {
 	r := &Reconciler{
-		client: mgr.GetClient(),
-		scheme: mgr.GetScheme(),
-		config: mgr.GetConfig(),
+		client:     mgr.GetClient(),
+		scheme:     mgr.GetScheme(),
+		restConfig: mgr.GetConfig(),
+		cfg:        cfg,
 	}
 
 	// Create a new controller
-	c, err := controller.New("topo-kind-controller", mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName, mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: config.MaxConcurrentReconciles(cfg.Get(), controllerName),
+	})
 	if err != nil {
 		return err
 	}
@@ -76,6 +85,19 @@ func Add(mgr manager.Manager) error {
 		return err
 	}
 
+	// Watch for changes made to the Kind's topo object directly in onos-topo so drift from other
+	// writers is picked up without waiting on the next update to the CR
+	topoSource := &grpc.TopoWatchSource{
+		GVK:       v1beta1.SchemeGroupVersion.WithKind("Kind"),
+		Namespace: grpc.WatchNamespace(),
+	}
+	if err := c.Watch(topoSource, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+	if err := topoSource.AddToManager(mgr); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -83,19 +105,24 @@ var _ reconcile.Reconciler = &Reconciler{}
 
 // Reconciler reconciles a Kind object
 type Reconciler struct {
-	client client.Client
-	scheme *runtime.Scheme
-	config *rest.Config
+	client     client.Client
+	scheme     *runtime.Scheme
+	restConfig *rest.Config
+	cfg        *config.Store
 }
 
 // Reconcile reads that state of the cluster for a Kind object and makes changes based on the state read
 // and what is in the Kind.Spec
-func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
-	log.Infof("Reconciling Kind %s/%s", request.Namespace, request.Name)
+func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.Get().ReconcileTimeout.Duration)
+	defer cancel()
+
+	ctx, reqLog := ctxlog.NewReconcileContext(ctx, log, "Kind", request)
+	reqLog.Infof("Reconciling Kind %s/%s", request.Namespace, request.Name)
 
 	// Fetch the Kind instance
 	kind := &v1beta1.Kind{}
-	err := r.client.Get(context.TODO(), request.NamespacedName, kind)
+	err := r.client.Get(ctx, request.NamespacedName, kind)
 	if err != nil {
 		if k8serrors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
@@ -108,53 +135,121 @@ func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, err
 	}
 
 	if kind.DeletionTimestamp == nil {
-		return r.reconcileCreate(kind)
+		return r.reconcileCreate(ctx, kind)
 	} else {
-		return r.reconcileDelete(kind)
+		return r.reconcileDelete(ctx, kind)
 	}
 }
 
-func (r *Reconciler) reconcileCreate(kind *v1beta1.Kind) (reconcile.Result, error) {
+func (r *Reconciler) reconcileCreate(ctx context.Context, kind *v1beta1.Kind) (reconcile.Result, error) {
+	cfg := r.cfg.Get()
+
 	// Add the finalizer to the kind if necessary
-	if !k8s.HasFinalizer(kind, topoFinalizer) {
-		k8s.AddFinalizer(kind, topoFinalizer)
-		err := r.client.Update(context.TODO(), kind)
+	if !k8s.HasFinalizer(kind, cfg.FinalizerName) {
+		k8s.AddFinalizer(kind, cfg.FinalizerName)
+		err := r.client.Update(ctx, kind)
 		if err != nil {
 			return reconcile.Result{}, err
 		}
 	}
 
 	// Connect to the topology service
-	conn, err := grpc.ConnectService(r.client, kind.Namespace, topoService)
+	connectCtx, cancel := context.WithTimeout(ctx, cfg.TopoConnectTimeout.Duration)
+	defer cancel()
+	conn, err := grpc.ConnectService(connectCtx, r.client, kind.Namespace, cfg.TopoServiceName)
 	if err != nil {
-		return reconcile.Result{}, err
+		r.setCondition(kind, metav1.Condition{
+			Type:    v1beta1.ConditionTopoReachable,
+			Status:  metav1.ConditionFalse,
+			Reason:  v1beta1.ReasonTopoUnreachable,
+			Message: err.Error(),
+		})
+		return reconcile.Result{}, r.updateStatus(ctx, kind)
 	}
 	defer conn.Close()
 
 	client := topo.NewTopoClient(conn)
+	r.setCondition(kind, metav1.Condition{
+		Type:   v1beta1.ConditionTopoReachable,
+		Status: metav1.ConditionTrue,
+		Reason: "Connected",
+	})
 
-	// Check if the kind exists in the topology and exit reconciliation if so
-	if exists, err := r.kindExists(kind, client); err != nil {
+	// Fetch the object as stored in onos-topo, if it exists
+	object, exists, err := r.getKind(ctx, kind, client)
+	if err != nil {
+		r.setCondition(kind, metav1.Condition{
+			Type:    v1beta1.ConditionSynced,
+			Status:  metav1.ConditionFalse,
+			Reason:  v1beta1.ReasonGetFailed,
+			Message: err.Error(),
+		})
+		if statusErr := r.updateStatus(ctx, kind); statusErr != nil {
+			return reconcile.Result{}, statusErr
+		}
 		return reconcile.Result{}, err
-	} else if exists {
-		return reconcile.Result{}, nil
 	}
 
-	// If the kind does not exist, create it
-	if err := r.createKind(kind, client); err != nil {
+	if !exists {
+		// The kind does not exist in onos-topo yet; create it
+		if err := r.createKind(ctx, kind, client); err != nil {
+			return reconcile.Result{}, err
+		}
+		r.setCondition(kind, metav1.Condition{
+			Type:   v1beta1.ConditionSynced,
+			Status: metav1.ConditionTrue,
+			Reason: v1beta1.ReasonSynced,
+		})
+	} else if merged, drifted, err := topodiff.Merge(desiredKind(kind), object, topodiff.OwnedPaths(kind.Annotations)); err != nil {
 		return reconcile.Result{}, err
+	} else if drifted {
+		// The owned fields of the in-cluster spec have drifted from onos-topo; re-issue the update
+		// with only the owned paths merged in, leaving everything else as onos-topo reported it
+		if err := r.updateKind(ctx, kind, merged, client); err != nil {
+			r.setCondition(kind, metav1.Condition{
+				Type:    v1beta1.ConditionSynced,
+				Status:  metav1.ConditionFalse,
+				Reason:  v1beta1.ReasonDrifted,
+				Message: err.Error(),
+			})
+			return reconcile.Result{}, r.updateStatus(ctx, kind)
+		}
+		r.setCondition(kind, metav1.Condition{
+			Type:   v1beta1.ConditionSynced,
+			Status: metav1.ConditionTrue,
+			Reason: v1beta1.ReasonSynced,
+		})
+	} else {
+		r.setCondition(kind, metav1.Condition{
+			Type:   v1beta1.ConditionSynced,
+			Status: metav1.ConditionTrue,
+			Reason: v1beta1.ReasonAlreadyExists,
+		})
 	}
-	return reconcile.Result{}, nil
+
+	r.setCondition(kind, metav1.Condition{
+		Type:   v1beta1.ConditionReady,
+		Status: metav1.ConditionTrue,
+		Reason: v1beta1.ReasonSynced,
+	})
+	kind.Status.ObservedGeneration = kind.Generation
+	now := metav1.Now()
+	kind.Status.LastSyncTime = &now
+	return reconcile.Result{}, r.updateStatus(ctx, kind)
 }
 
-func (r *Reconciler) reconcileDelete(kind *v1beta1.Kind) (reconcile.Result, error) {
+func (r *Reconciler) reconcileDelete(ctx context.Context, kind *v1beta1.Kind) (reconcile.Result, error) {
+	cfg := r.cfg.Get()
+
 	// If the kind has already been finalized, exit reconciliation
-	if !k8s.HasFinalizer(kind, topoFinalizer) {
+	if !k8s.HasFinalizer(kind, cfg.FinalizerName) {
 		return reconcile.Result{}, nil
 	}
 
 	// Connect to the topology service
-	conn, err := grpc.ConnectService(r.client, kind.Namespace, topoService)
+	connectCtx, cancel := context.WithTimeout(ctx, cfg.TopoConnectTimeout.Duration)
+	defer cancel()
+	conn, err := grpc.ConnectService(connectCtx, r.client, kind.Namespace, cfg.TopoServiceName)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
@@ -163,55 +258,91 @@ func (r *Reconciler) reconcileDelete(kind *v1beta1.Kind) (reconcile.Result, erro
 	client := topo.NewTopoClient(conn)
 
 	// Delete the kind from the topology
-	if err := r.deleteKind(kind, client); err != nil {
+	if err := r.deleteKind(ctx, kind, client); err != nil {
 		return reconcile.Result{}, err
 	}
 
 	// Once the kind has been deleted, remove the topology finalizer
-	k8s.RemoveFinalizer(kind, topoFinalizer)
-	if err := r.client.Update(context.TODO(), kind); err != nil {
+	k8s.RemoveFinalizer(kind, cfg.FinalizerName)
+	if err := r.client.Update(ctx, kind); err != nil {
 		return reconcile.Result{}, err
 	}
 	return reconcile.Result{}, nil
 }
 
-func (r *Reconciler) kindExists(kind *v1beta1.Kind, client topo.TopoClient) (bool, error) {
+func (r *Reconciler) getKind(ctx context.Context, kind *v1beta1.Kind, client topo.TopoClient) (*topo.Object, bool, error) {
 	request := &topo.GetRequest{
 		ID: topo.ID(kind.Name),
 	}
-	_, err := client.Get(context.TODO(), request)
+	response, err := client.Get(ctx, request)
 	if err == nil {
-		return true, nil
+		return response.Object, true, nil
 	}
 
 	stat, ok := status.FromError(err)
 	if !ok {
-		return false, err
+		return nil, false, err
 	}
 
 	err = errors.FromStatus(stat)
 	if !errors.IsNotFound(err) {
-		return false, err
+		return nil, false, err
 	}
-	return false, nil
+	return nil, false, nil
 }
 
-func (r *Reconciler) createKind(kind *v1beta1.Kind, client topo.TopoClient) error {
-	request := &topo.CreateRequest{
-		Object: &topo.Object{
-			ID:   topo.ID(kind.Name),
-			Type: topo.Object_KIND,
-			Obj: &topo.Object_Kind{
-				Kind: &topo.Kind{
-					Name:       kind.Name,
-					Attributes: kind.Spec.Attributes,
-				},
+// desiredKind returns the topo.Object representing the full desired state of a Kind, used as the
+// local side of a topodiff.Merge against what onos-topo currently reports
+func desiredKind(kind *v1beta1.Kind) *topo.Object {
+	return &topo.Object{
+		ID:   topo.ID(kind.Name),
+		Type: topo.Object_KIND,
+		Obj: &topo.Object_Kind{
+			Kind: &topo.Kind{
+				Name:       kind.Name,
+				Attributes: kind.Spec.Attributes,
 			},
-			Attributes: kind.Spec.Attributes,
 		},
+		Attributes: kind.Spec.Attributes,
+	}
+}
+
+func (r *Reconciler) updateKind(ctx context.Context, kind *v1beta1.Kind, merged *topo.Object, client topo.TopoClient) error {
+	request := &topo.UpdateRequest{
+		Object: merged,
+	}
+
+	_, err := client.Update(ctx, request)
+	return err
+}
+
+// setCondition sets or updates a condition on the Kind's status, preserving LastTransitionTime when the
+// condition's status is unchanged
+func (r *Reconciler) setCondition(kind *v1beta1.Kind, condition metav1.Condition) {
+	condition.ObservedGeneration = kind.Generation
+	condition.LastTransitionTime = metav1.Now()
+	for i, existing := range kind.Status.Conditions {
+		if existing.Type == condition.Type {
+			if existing.Status == condition.Status {
+				condition.LastTransitionTime = existing.LastTransitionTime
+			}
+			kind.Status.Conditions[i] = condition
+			return
+		}
+	}
+	kind.Status.Conditions = append(kind.Status.Conditions, condition)
+}
+
+func (r *Reconciler) updateStatus(ctx context.Context, kind *v1beta1.Kind) error {
+	return r.client.Status().Update(ctx, kind)
+}
+
+func (r *Reconciler) createKind(ctx context.Context, kind *v1beta1.Kind, client topo.TopoClient) error {
+	request := &topo.CreateRequest{
+		Object: desiredKind(kind),
 	}
 
-	_, err := client.Create(context.TODO(), request)
+	_, err := client.Create(ctx, request)
 	if err == nil {
 		return nil
 	}
@@ -228,12 +359,12 @@ func (r *Reconciler) createKind(kind *v1beta1.Kind, client topo.TopoClient) erro
 	return nil
 }
 
-func (r *Reconciler) deleteKind(kind *v1beta1.Kind, client topo.TopoClient) error {
+func (r *Reconciler) deleteKind(ctx context.Context, kind *v1beta1.Kind, client topo.TopoClient) error {
 	request := &topo.DeleteRequest{
 		ID: topo.ID(kind.Name),
 	}
 
-	_, err := client.Delete(context.TODO(), request)
+	_, err := client.Delete(ctx, request)
 	if err == nil {
 		return nil
 	}
@@ -0,0 +1,125 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package config loads and hot-reloads the OnosOperatorConfig that tunes the operator's controllers,
+// in place of the hardcoded constants each controller package used to carry.
+package config
+
+import (
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/onos-operator/pkg/apis/config/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+var log = logging.GetLogger("controller", "util", "config")
+
+const (
+	// DefaultTopoServiceName is used when OnosOperatorConfig.TopoServiceName is unset
+	DefaultTopoServiceName = "onos-topo"
+
+	// DefaultFinalizerName is used when OnosOperatorConfig.FinalizerName is unset
+	DefaultFinalizerName = "topo"
+
+	// DefaultTopoConnectTimeout is used when OnosOperatorConfig.TopoConnectTimeout is unset
+	DefaultTopoConnectTimeout = 5 * time.Second
+
+	// DefaultReconcileTimeout is used when OnosOperatorConfig.ReconcileTimeout is unset
+	DefaultReconcileTimeout = 30 * time.Second
+
+	// DefaultMaxConcurrentReconciles is used for a controller with no entry in OnosOperatorConfig.Controllers
+	DefaultMaxConcurrentReconciles = 1
+)
+
+// Default returns an OnosOperatorConfig populated with this package's defaults, used when the operator is
+// started without a --config flag
+func Default() *v1beta1.OnosOperatorConfig {
+	cfg := &v1beta1.OnosOperatorConfig{}
+	applyDefaults(cfg)
+	return cfg
+}
+
+// Load reads an OnosOperatorConfig from the YAML file at path, filling in this package's defaults for any
+// field the file leaves unset
+func Load(path string) (*v1beta1.OnosOperatorConfig, error) {
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := Default()
+	if err := yaml.Unmarshal(bytes, cfg); err != nil {
+		return nil, err
+	}
+	applyDefaults(cfg)
+	return cfg, nil
+}
+
+// applyDefaults fills in any field left unset by a partial config file
+func applyDefaults(cfg *v1beta1.OnosOperatorConfig) {
+	if cfg.TopoServiceName == "" {
+		cfg.TopoServiceName = DefaultTopoServiceName
+	}
+	if cfg.FinalizerName == "" {
+		cfg.FinalizerName = DefaultFinalizerName
+	}
+	if cfg.TopoConnectTimeout.Duration == 0 {
+		cfg.TopoConnectTimeout = metav1.Duration{Duration: DefaultTopoConnectTimeout}
+	}
+	if cfg.ReconcileTimeout.Duration == 0 {
+		cfg.ReconcileTimeout = metav1.Duration{Duration: DefaultReconcileTimeout}
+	}
+}
+
+// MaxConcurrentReconciles returns the tuned worker count for the named controller, or
+// DefaultMaxConcurrentReconciles if cfg has no entry for it
+func MaxConcurrentReconciles(cfg *v1beta1.OnosOperatorConfig, controllerName string) int {
+	if cfg == nil {
+		return DefaultMaxConcurrentReconciles
+	}
+	if c, ok := cfg.Controllers[controllerName]; ok && c.MaxConcurrentReconciles > 0 {
+		return c.MaxConcurrentReconciles
+	}
+	return DefaultMaxConcurrentReconciles
+}
+
+// Store holds the operator's current OnosOperatorConfig and allows it to be swapped out as the backing
+// file is hot-reloaded by a Watcher, without requiring every holder of the Store to re-fetch it from disk
+type Store struct {
+	mu  sync.RWMutex
+	cfg *v1beta1.OnosOperatorConfig
+}
+
+// NewStore returns a Store seeded with cfg
+func NewStore(cfg *v1beta1.OnosOperatorConfig) *Store {
+	return &Store{cfg: cfg}
+}
+
+// Get returns the Store's current config
+func (s *Store) Get() *v1beta1.OnosOperatorConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cfg
+}
+
+// set replaces the Store's current config, called by a Watcher when the backing file changes
+func (s *Store) set(cfg *v1beta1.OnosOperatorConfig) {
+	s.mu.Lock()
+	s.cfg = cfg
+	s.mu.Unlock()
+}
@@ -0,0 +1,98 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watcher reloads a Store's config whenever the backing file at Path changes, so routine tuning (topo
+// connection timeouts, the finalizer name, per-controller concurrency) doesn't require rebuilding and
+// redeploying the operator image.
+//
+// MaxConcurrentReconciles is not applied live: controller-runtime sizes a controller's worker pool once,
+// in controller.New, and does not expose a way to resize it after Start. A changed value is picked up on
+// the operator's next restart; until then the Watcher only logs that the running worker count is stale.
+// Changes to the embedded controller-runtime ComponentConfig fields are not applied to a running manager
+// either.
+type Watcher struct {
+	// Path is the config file to watch
+	Path string
+
+	// Store receives every successfully reloaded config
+	Store *Store
+
+	watcher *fsnotify.Watcher
+}
+
+// Start implements manager.Runnable, watching Path for writes until ctx is cancelled. It watches Path's
+// parent directory rather than Path itself: a file mounted from a Kubernetes ConfigMap is updated by
+// kubelet swapping a `..data` symlink with a rename, which a watch on Path alone would miss once the
+// original inode is replaced.
+func (w *Watcher) Start(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	w.watcher = watcher
+	defer watcher.Close()
+
+	if err := watcher.Add(filepath.Dir(w.Path)); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			name := filepath.Clean(event.Name)
+			if name != filepath.Clean(w.Path) && filepath.Base(name) != "..data" {
+				continue
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0 {
+				w.reload()
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Warnf("Error watching %s for changes: %v", w.Path, err)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (w *Watcher) reload() {
+	cfg, err := Load(w.Path)
+	if err != nil {
+		log.Warnf("Failed reloading %s: %v", w.Path, err)
+		return
+	}
+
+	old := w.Store.Get()
+	w.Store.set(cfg)
+	log.Infof("Reloaded config from %s", w.Path)
+
+	if old != nil && !reflect.DeepEqual(old.Controllers, cfg.Controllers) {
+		log.Warnf("Controllers concurrency changed in %s; restart the operator for it to take effect", w.Path)
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	libgrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ConnectService resolves the named Service in namespace through the Kubernetes API and dials its first
+// port over plaintext gRPC, blocking until the connection is established or ctx is done.
+func ConnectService(ctx context.Context, c client.Client, namespace, name string) (*libgrpc.ClientConn, error) {
+	service := &corev1.Service{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, service); err != nil {
+		return nil, err
+	}
+
+	if len(service.Spec.Ports) == 0 {
+		return nil, fmt.Errorf("service %s/%s declares no ports", namespace, name)
+	}
+
+	target := fmt.Sprintf("%s.%s.svc.cluster.local:%d", name, namespace, service.Spec.Ports[0].Port)
+	return libgrpc.DialContext(ctx, target,
+		libgrpc.WithTransportCredentials(insecure.NewCredentials()),
+		libgrpc.WithBlock())
+}
@@ -0,0 +1,216 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var watchLog = logging.GetLogger("controller", "util", "grpc")
+
+// minWatchBackoff/maxWatchBackoff bound the reconnect delay for a broken onos-topo Watch stream
+const (
+	minWatchBackoff = time.Second
+	maxWatchBackoff = 30 * time.Second
+)
+
+// WatchNamespaceEnvVar is the environment variable operator-sdk-style scaffolding sets to the namespace
+// this operator instance is deployed to watch
+const WatchNamespaceEnvVar = "WATCH_NAMESPACE"
+
+// WatchNamespace returns the namespace this operator instance watches, as set by WatchNamespaceEnvVar
+func WatchNamespace() string {
+	return os.Getenv(WatchNamespaceEnvVar)
+}
+
+// gvkObjectTypes maps the Kind of a topo CR's GroupVersionKind to the onos-topo object type it mirrors
+var gvkObjectTypes = map[string]topo.Object_Type{
+	"Kind":     topo.Object_KIND,
+	"Entity":   topo.Object_ENTITY,
+	"Relation": topo.Object_RELATION,
+}
+
+// TopoWatchSource is a controller-runtime source.Source that watches onos-topo directly, rather than
+// the Kubernetes API server, so that changes made to a topo object outside of the operator (e.g. by the
+// CLI or another client) are picked up without waiting for the owning CR to be written to again.
+//
+// A reconcile.Request is enqueued for any event whose object Type matches GVK.Kind, using Namespace as
+// the namespace of the CR and the object's ID as its name; events for other object types are ignored so
+// that the Kind, Entity, and Relation controllers - which each run their own TopoWatchSource against the
+// same stream of events - only react to the one kind they own.
+//
+// predicates passed to Start are accepted for interface compatibility with source.Kind but are not
+// evaluated, since this source never has more than a namespace/name pair to test them against.
+type TopoWatchSource struct {
+	// Client is used to resolve the onos-topo Service's address
+	Client client.Client
+
+	// GVK is the kind of CR to enqueue reconcile.Requests for
+	GVK schema.GroupVersionKind
+
+	// Namespace is the namespace of both the onos-topo service and the CRs to enqueue
+	Namespace string
+
+	// ServiceName is the name of the onos-topo service to connect to; defaults to "onos-topo"
+	ServiceName string
+
+	mu      sync.Mutex
+	ctx     context.Context
+	objType topo.Object_Type
+	started bool
+}
+
+// Start implements source.Source, spinning up a goroutine that streams events from onos-topo and
+// translates them into reconcile.Requests for the configured GVK. The goroutine auto-reconnects with
+// exponential backoff and exits once the manager tears it down via AddToManager.
+func (s *TopoWatchSource) Start(eventHandler handler.EventHandler, queue workqueue.RateLimitingInterface, predicates ...predicate.Predicate) error {
+	s.mu.Lock()
+	if s.started {
+		s.mu.Unlock()
+		return nil
+	}
+	s.started = true
+	if s.ServiceName == "" {
+		s.ServiceName = "onos-topo"
+	}
+	s.objType = gvkObjectTypes[s.GVK.Kind]
+	s.mu.Unlock()
+
+	go s.run(queue)
+	return nil
+}
+
+// AddToManager registers the source as a manager.Runnable so that its background goroutine is
+// cancelled when the manager's context is cancelled on shutdown
+func (s *TopoWatchSource) AddToManager(mgr manager.Manager) error {
+	if s.Client == nil {
+		s.Client = mgr.GetClient()
+	}
+	return mgr.Add(&watchRunnable{source: s})
+}
+
+// watchRunnable ties the lifetime of the TopoWatchSource's background goroutine to the manager
+type watchRunnable struct {
+	source *TopoWatchSource
+}
+
+func (r *watchRunnable) Start(ctx context.Context) error {
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	r.source.mu.Lock()
+	r.source.ctx = runCtx
+	r.source.mu.Unlock()
+
+	<-ctx.Done()
+	return nil
+}
+
+func (s *TopoWatchSource) run(queue workqueue.RateLimitingInterface) {
+	backoff := minWatchBackoff
+	for {
+		ctx := s.context()
+		if ctx.Err() != nil {
+			return
+		}
+
+		conn, err := ConnectService(ctx, s.Client, s.Namespace, s.ServiceName)
+		if err != nil {
+			watchLog.Warnf("Failed connecting to onos-topo for watch: %v", err)
+			backoff = s.sleep(ctx, backoff)
+			continue
+		}
+
+		client := topo.NewTopoClient(conn)
+		stream, err := client.Watch(ctx, &topo.WatchRequest{})
+		if err != nil {
+			conn.Close()
+			watchLog.Warnf("Failed opening onos-topo watch stream: %v", err)
+			backoff = s.sleep(ctx, backoff)
+			continue
+		}
+
+		// The stream connected; reset the backoff so a later disconnect starts retrying quickly again
+		backoff = minWatchBackoff
+		s.receive(stream, queue)
+
+		conn.Close()
+		if ctx.Err() != nil {
+			return
+		}
+		backoff = s.sleep(ctx, backoff)
+	}
+}
+
+func (s *TopoWatchSource) receive(stream topo.Topo_WatchClient, queue workqueue.RateLimitingInterface) {
+	for {
+		response, err := stream.Recv()
+		if err != nil {
+			watchLog.Warnf("onos-topo watch stream closed: %v", err)
+			return
+		}
+
+		if response.Event.Object.Type != s.objType {
+			continue
+		}
+
+		switch response.Event.Type {
+		case topo.EventType_UPDATED, topo.EventType_REMOVED:
+			queue.Add(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: s.Namespace,
+					Name:      string(response.Event.Object.ID),
+				},
+			})
+		}
+	}
+}
+
+func (s *TopoWatchSource) sleep(ctx context.Context, backoff time.Duration) time.Duration {
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+	next := backoff * 2
+	if next > maxWatchBackoff {
+		next = maxWatchBackoff
+	}
+	return next
+}
+
+func (s *TopoWatchSource) context() context.Context {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ctx == nil {
+		return context.Background()
+	}
+	return s.ctx
+}
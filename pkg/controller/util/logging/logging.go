@@ -0,0 +1,56 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package logging provides a context-scoped logger so reconcilers can annotate every log line with the
+// reconcile.Request key and a per-invocation ID without threading a logger through every function
+// signature.
+package logging
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type contextKey string
+
+const loggerKey contextKey = "logger"
+
+// NewContext returns a copy of ctx carrying the given logger, retrievable with FromContext
+func NewContext(ctx context.Context, log logging.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, log)
+}
+
+// FromContext returns the logger embedded in ctx, or the given fallback logger if none was embedded
+func FromContext(ctx context.Context, fallback logging.Logger) logging.Logger {
+	if log, ok := ctx.Value(loggerKey).(logging.Logger); ok {
+		return log
+	}
+	return fallback
+}
+
+// NewReconcileContext returns a copy of ctx carrying a logger derived from base, annotated with the
+// reconcile request's namespace/name/gvk and a fresh per-invocation ID, along with the embedded logger
+// for immediate use
+func NewReconcileContext(ctx context.Context, base logging.Logger, gvk string, request reconcile.Request) (context.Context, logging.Logger) {
+	log := base.WithFields(
+		"gvk", gvk,
+		"namespace", request.Namespace,
+		"name", request.Name,
+		"reconcileID", uuid.New().String(),
+	)
+	return NewContext(ctx, log), log
+}
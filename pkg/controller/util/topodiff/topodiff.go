@@ -0,0 +1,193 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package topodiff implements property-scoped reconciliation of topo.Object resources: only the
+// fields named by a set of JSONPath expressions are treated as owned by the CR and merged into the
+// object stored in onos-topo. Fields outside that surface are left untouched so that other writers
+// to onos-topo don't cause reconcile churn.
+package topodiff
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// OwnedPathsAnnotation is the annotation operators can set on a CR to override the set of JSONPath
+// expressions reconciled into onos-topo, e.g. to opt individual fields out of reconciliation
+const OwnedPathsAnnotation = "topo.onosproject.org/reconciled-paths"
+
+// DefaultOwnedPaths is the set of JSONPath expressions reconciled into onos-topo when a CR does not
+// override them via OwnedPathsAnnotation
+var DefaultOwnedPaths = []string{
+	"$.attributes.*",
+	"$.kind.name",
+	"$.entity.kindId",
+	"$.relation.kindId",
+	"$.relation.srcEntityId",
+	"$.relation.tgtEntityId",
+}
+
+// OwnedPaths returns the set of JSONPath expressions owned by the CR with the given annotations,
+// falling back to DefaultOwnedPaths if the CR has not overridden them
+func OwnedPaths(annotations map[string]string) []string {
+	raw, ok := annotations[OwnedPathsAnnotation]
+	if !ok || strings.TrimSpace(raw) == "" {
+		return DefaultOwnedPaths
+	}
+
+	var paths []string
+	for _, path := range strings.Split(raw, ",") {
+		path = strings.TrimSpace(path)
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// Merge returns a copy of remote with the fields named by paths overwritten with the corresponding
+// values from local, along with whether the merge changed remote in an owned field. Fields not named
+// by paths are left exactly as they were in remote. When no owned field changed, remote itself is
+// returned rather than a copy.
+func Merge(local, remote *topo.Object, paths []string) (*topo.Object, bool, error) {
+	localMap, err := toMap(local)
+	if err != nil {
+		return nil, false, err
+	}
+
+	remoteMap, err := toMap(remote)
+	if err != nil {
+		return nil, false, err
+	}
+
+	changed := false
+	for _, path := range paths {
+		if applyPath(remoteMap, localMap, path) {
+			changed = true
+		}
+	}
+
+	if !changed {
+		return remote, false, nil
+	}
+
+	merged := &topo.Object{}
+	if err := fromMap(remoteMap, merged); err != nil {
+		return nil, false, err
+	}
+	return merged, true, nil
+}
+
+// applyPath copies the value at path from src into dst, reporting whether it changed dst
+func applyPath(dst, src map[string]interface{}, path string) bool {
+	segments := strings.Split(strings.TrimPrefix(path, "$."), ".")
+	return setPath(dst, src, segments)
+}
+
+func setPath(dst, src map[string]interface{}, segments []string) bool {
+	segment := segments[0]
+
+	if segment == "*" {
+		// A wildcard owns the whole map, not just the keys present in src, so it's a full
+		// replace: a key removed from src (e.g. an attribute deleted from Spec.Attributes) must
+		// disappear from dst too, not just have its siblings merged in.
+		if equal(dst, src) {
+			return false
+		}
+		for key := range dst {
+			delete(dst, key)
+		}
+		for key, value := range src {
+			dst[key] = value
+		}
+		return true
+	}
+
+	if len(segments) == 1 {
+		srcValue, ok := lookup(src, segment)
+		if !ok {
+			return false
+		}
+		if existing, ok := dst[segment]; ok && equal(existing, srcValue) {
+			return false
+		}
+		dst[segment] = srcValue
+		return true
+	}
+
+	childSrc, ok := lookup(src, segment)
+	if !ok {
+		// protojson omits an empty map, so a wildcard's parent missing from src means src holds an
+		// empty map, which the wildcard must still apply; any other missing value is left alone
+		if len(segments) != 2 || segments[1] != "*" {
+			return false
+		}
+		childSrc = map[string]interface{}{}
+	}
+	childSrcMap, ok := childSrc.(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	childDst, ok := dst[segment].(map[string]interface{})
+	if !ok {
+		childDst = map[string]interface{}{}
+		dst[segment] = childDst
+	}
+	return setPath(childDst, childSrcMap, segments[1:])
+}
+
+func lookup(obj interface{}, key string) (interface{}, bool) {
+	m, ok := obj.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	value, ok := m[key]
+	return value, ok
+}
+
+func equal(a, b interface{}) bool {
+	aBytes, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bBytes, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(aBytes) == string(bBytes)
+}
+
+func toMap(object *topo.Object) (map[string]interface{}, error) {
+	data, err := protojson.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func fromMap(m map[string]interface{}, object *topo.Object) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return protojson.Unmarshal(data, object)
+}
@@ -0,0 +1,171 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topodiff
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/onosproject/onos-api/go/onos/topo"
+)
+
+func kindObject(name string, attrs map[string]string) *topo.Object {
+	return &topo.Object{
+		ID:   "kind-1",
+		Type: topo.Object_KIND,
+		Obj: &topo.Object_Kind{
+			Kind: &topo.Kind{
+				Name:       name,
+				Attributes: attrs,
+			},
+		},
+		Attributes: attrs,
+	}
+}
+
+func entityObject(kindID string, attrs map[string]string) *topo.Object {
+	return &topo.Object{
+		ID:   "entity-1",
+		Type: topo.Object_ENTITY,
+		Obj: &topo.Object_Entity{
+			Entity: &topo.Entity{
+				KindID: topo.ID(kindID),
+			},
+		},
+		Attributes: attrs,
+	}
+}
+
+func relationObject(kindID, src, tgt string, attrs map[string]string) *topo.Object {
+	return &topo.Object{
+		ID:   "relation-1",
+		Type: topo.Object_RELATION,
+		Obj: &topo.Object_Relation{
+			Relation: &topo.Relation{
+				KindID:      topo.ID(kindID),
+				SrcEntityID: topo.ID(src),
+				TgtEntityID: topo.ID(tgt),
+			},
+		},
+		Attributes: attrs,
+	}
+}
+
+// TestMergeAttributesWildcard exercises the "$.attributes.*" path against real protojson output: it
+// must replace the whole attributes map, including dropping a key present in remote but absent from
+// local, not just overwrite the keys local happens to have.
+func TestMergeAttributesWildcard(t *testing.T) {
+	local := kindObject("switch", map[string]string{"a": "1", "b": "2"})
+	remote := kindObject("switch", map[string]string{"a": "0", "c": "3"})
+
+	merged, drifted, err := Merge(local, remote, []string{"$.attributes.*"})
+	if err != nil {
+		t.Fatalf("Merge returned error: %v", err)
+	}
+	if !drifted {
+		t.Fatal("expected drifted to be true")
+	}
+	if !reflect.DeepEqual(merged.Attributes, local.Attributes) {
+		t.Fatalf("expected attributes %v, got %v", local.Attributes, merged.Attributes)
+	}
+}
+
+// TestMergeNoDrift asserts that Merge is a no-op, returning remote unchanged, when local and remote
+// already agree on every owned path.
+func TestMergeNoDrift(t *testing.T) {
+	local := kindObject("switch", map[string]string{"a": "1"})
+	remote := kindObject("switch", map[string]string{"a": "1"})
+
+	merged, drifted, err := Merge(local, remote, DefaultOwnedPaths)
+	if err != nil {
+		t.Fatalf("Merge returned error: %v", err)
+	}
+	if drifted {
+		t.Fatal("expected drifted to be false")
+	}
+	if merged != remote {
+		t.Fatal("expected merge to return remote unchanged when nothing drifted")
+	}
+}
+
+// TestMergeKindName guards against the oneof/camelCase path bug: "$.kind.name" must resolve against
+// protojson's actual encoding of a Kind object, where the oneof's chosen field ("kind") sits at the top
+// level rather than nested under "obj".
+func TestMergeKindName(t *testing.T) {
+	local := kindObject("spine", map[string]string{})
+	remote := kindObject("leaf", map[string]string{})
+
+	merged, drifted, err := Merge(local, remote, []string{"$.kind.name"})
+	if err != nil {
+		t.Fatalf("Merge returned error: %v", err)
+	}
+	if !drifted {
+		t.Fatal("expected drifted to be true")
+	}
+	if merged.GetKind().GetName() != "spine" {
+		t.Fatalf("expected merged kind name %q, got %q", "spine", merged.GetKind().GetName())
+	}
+}
+
+// TestMergeEntityKindID guards the "$.entity.kindId" path, which must match protojson's lowerCamelCase
+// encoding of the Entity.KindID field, not the proto field's snake_case name.
+func TestMergeEntityKindID(t *testing.T) {
+	local := entityObject("switch", map[string]string{})
+	remote := entityObject("router", map[string]string{})
+
+	merged, drifted, err := Merge(local, remote, []string{"$.entity.kindId"})
+	if err != nil {
+		t.Fatalf("Merge returned error: %v", err)
+	}
+	if !drifted {
+		t.Fatal("expected drifted to be true")
+	}
+	if merged.GetEntity().GetKindID() != "switch" {
+		t.Fatalf("expected merged entity kindId %q, got %q", "switch", merged.GetEntity().GetKindID())
+	}
+}
+
+// TestMergeRelationIdentity guards the three relation identity paths together.
+func TestMergeRelationIdentity(t *testing.T) {
+	local := relationObject("link", "eth0", "eth1", map[string]string{})
+	remote := relationObject("stale-link", "stale-src", "stale-tgt", map[string]string{})
+
+	paths := []string{"$.relation.kindId", "$.relation.srcEntityId", "$.relation.tgtEntityId"}
+	merged, drifted, err := Merge(local, remote, paths)
+	if err != nil {
+		t.Fatalf("Merge returned error: %v", err)
+	}
+	if !drifted {
+		t.Fatal("expected drifted to be true")
+	}
+	relation := merged.GetRelation()
+	if relation.GetKindID() != "link" || relation.GetSrcEntityID() != "eth0" || relation.GetTgtEntityID() != "eth1" {
+		t.Fatalf("expected relation identity (link, eth0, eth1), got (%s, %s, %s)",
+			relation.GetKindID(), relation.GetSrcEntityID(), relation.GetTgtEntityID())
+	}
+}
+
+// TestOwnedPaths covers both the default path set and an annotation override.
+func TestOwnedPaths(t *testing.T) {
+	if !reflect.DeepEqual(OwnedPaths(nil), DefaultOwnedPaths) {
+		t.Fatal("expected nil annotations to fall back to DefaultOwnedPaths")
+	}
+
+	paths := OwnedPaths(map[string]string{OwnedPathsAnnotation: "$.attributes.*, $.kind.name"})
+	expected := []string{"$.attributes.*", "$.kind.name"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Fatalf("expected %v, got %v", expected, paths)
+	}
+}